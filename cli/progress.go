@@ -35,66 +35,199 @@ type ProgressTrackerWithStatus interface {
 	Status() *ProgressUpdate
 }
 
+// RateStatus extends ProgressUpdate with smoothed throughput and ETA, as
+// returned by ProgressTrackerWithRate.StatusWithRate.
+type RateStatus struct {
+	*ProgressUpdate
+	FilesPerSecond, BytesPerSecond float64
+	FileETA, ByteETA               time.Duration
+	HasFileETA, HasByteETA         bool
+}
+
+// ProgressTrackerWithRate is a ProgressTrackerWithStatus that also tracks
+// smoothed throughput and ETA, e.g. BoundedTracker.
+type ProgressTrackerWithRate interface {
+	ProgressTrackerWithStatus
+	StatusWithRate() *RateStatus
+}
+
 // NoTracker implements the ProgressTracker interface but does nothing.
 var NoTracker = &nopTracker{}
 
 // DefaultTracker prints a message on each update and on close.
 func DefaultTracker() ProgressTrackerWithStatus {
-	return &progressTracker{start: time.Now()}
+	return &progressTracker{ctx: context.Background(), start: time.Now(), sink: stdoutSink{}}
+}
+
+// BoundedTrackerOption configures a BoundedTracker.
+type BoundedTrackerOption func(*boundedTrackerConfig)
+
+type boundedTrackerConfig struct {
+	ewmaWindow  time.Duration
+	disableETA  bool
+	fileRate    RateEstimator
+	byteRate    RateEstimator
+	maxFileBars int
+}
+
+// WithMaxFileBars caps the number of simultaneously visible per-file
+// sub-bars, collapsing the rest into a "+N more" summary. Defaults to 8.
+func WithMaxFileBars(n int) BoundedTrackerOption {
+	return func(c *boundedTrackerConfig) {
+		c.maxFileBars = n
+	}
+}
+
+// WithEWMAWindow sets the smoothing window used for the default
+// exponentially-weighted moving average rate estimator. Ignored if
+// WithRateEstimator is also passed.
+func WithEWMAWindow(window time.Duration) BoundedTrackerOption {
+	return func(c *boundedTrackerConfig) {
+		c.ewmaWindow = window
+	}
+}
+
+// WithoutETA disables the ETA and rate decorators.
+func WithoutETA() BoundedTrackerOption {
+	return func(c *boundedTrackerConfig) {
+		c.disableETA = true
+	}
+}
+
+// WithRateEstimator swaps in a custom RateEstimator (e.g. NewSimpleAverageRate)
+// for both the file and byte bars in place of the default EWMA. newEstimator
+// is called once per bar, so each bar gets its own estimator.
+func WithRateEstimator(newEstimator func() RateEstimator) BoundedTrackerOption {
+	return func(c *boundedTrackerConfig) {
+		c.fileRate = newEstimator()
+		c.byteRate = newEstimator()
+	}
 }
 
 // BoundedTracker shows the progress of an operation with a predefined size.
 // Falls back to DefaultTracker if not in a terminal.
-func BoundedTracker(ctx context.Context, totalFiles, totalBytes int64) ProgressTrackerWithStatus {
+func BoundedTracker(ctx context.Context, totalFiles, totalBytes int64, opts ...BoundedTrackerOption) ProgressTrackerWithStatus {
 	if !terminal.IsTerminal(int(os.Stdout.Fd())) {
 		return DefaultTracker()
 	}
 
+	cfg := &boundedTrackerConfig{ewmaWindow: defaultEWMAWindow}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.fileRate == nil {
+		cfg.fileRate = NewEWMARate(cfg.ewmaWindow)
+	}
+	if cfg.byteRate == nil {
+		cfg.byteRate = NewEWMARate(cfg.ewmaWindow)
+	}
+
 	p := &ProgressUpdate{}
 	progress := mpb.NewWithContext(ctx, mpb.WithWidth(50))
+	fileBarAppend := []decor.Decorator{
+		percentageDecorator,
+		newDecorator(func(s *decor.Statistics) string {
+			if p.FilesPending == 0 {
+				return ""
+			}
+			return fmt.Sprintf(" %d in progress", p.FilesPending)
+		}),
+	}
+	byteBarAppend := []decor.Decorator{
+		percentageDecorator,
+		newDecorator(func(s *decor.Statistics) string {
+			if p.BytesPending == 0 {
+				return ""
+			}
+			return fmt.Sprintf(" %s in progress", formatBytes(p.BytesPending))
+		}),
+	}
+	if !cfg.disableETA {
+		fileBarAppend = append(fileBarAppend, rateAndETADecorator(cfg.fileRate, false))
+		byteBarAppend = append(byteBarAppend, rateAndETADecorator(cfg.byteRate, true))
+	}
+	fileBarAppend = append(fileBarAppend, decor.OnComplete(decor.Spinner(nil, decor.WCSyncSpace), "✔"))
+	byteBarAppend = append(byteBarAppend, decor.OnComplete(decor.Spinner(nil, decor.WCSyncSpace), "✔"))
+
 	fileBar := progress.AddBar(totalFiles,
 		mpb.PrependDecorators(
 			decor.Name("Files: "),
 			ratioDecorator),
-		mpb.AppendDecorators(
-			percentageDecorator,
-			newDecorator(func(s *decor.Statistics) string {
-				if p.FilesPending == 0 {
-					return ""
-				}
-				return fmt.Sprintf(" %d in progress", p.FilesPending)
-			}),
-			decor.OnComplete(decor.Spinner(nil, decor.WCSyncSpace), "✔")))
+		mpb.AppendDecorators(fileBarAppend...))
 	byteBar := progress.AddBar(totalBytes,
 		mpb.PrependDecorators(
 			decor.Name("Bytes: "),
 			byteRatioDecorator),
-		mpb.AppendDecorators(
-			percentageDecorator,
-			newDecorator(func(s *decor.Statistics) string {
-				if p.BytesPending == 0 {
-					return ""
-				}
-				return fmt.Sprintf(" %s in progress", formatBytes(p.BytesPending))
-			}),
-			decor.OnComplete(decor.Spinner(nil, decor.WCSyncSpace), "✔")))
+		mpb.AppendDecorators(byteBarAppend...))
 
 	return &boundedTracker{
-		start:    time.Now(),
-		p:        p,
-		progress: progress,
-		fileBar:  fileBar,
-		byteBar:  byteBar,
+		ctx:        ctx,
+		start:      time.Now(),
+		p:          p,
+		progress:   progress,
+		fileBar:    fileBar,
+		byteBar:    byteBar,
+		fileRate:   cfg.fileRate,
+		byteRate:   cfg.byteRate,
+		totalFiles: totalFiles,
+		totalBytes: totalBytes,
+		files:      newFileBars(progress, cfg.maxFileBars),
+	}
+}
+
+// rateAndETADecorator renders the smoothed rate and ETA for a bar backed by
+// estimator. byteRate selects byte-oriented formatting; otherwise the rate is
+// shown as a plain count per second.
+func rateAndETADecorator(estimator RateEstimator, byteRate bool) decor.Decorator {
+	return newDecorator(func(s *decor.Statistics) string {
+		rate := estimator.Rate()
+		if rate <= 0 {
+			return ""
+		}
+
+		var rateStr string
+		if byteRate {
+			rateStr = FormatRate(int64(math.Round(rate)), time.Second)
+		} else {
+			rateStr = fmt.Sprintf("%.1f/s", rate)
+		}
+
+		remaining, ok := remainingETA(s.Current, s.Total, rate)
+		if !ok {
+			return fmt.Sprintf(" %s", rateStr)
+		}
+		return fmt.Sprintf(" %s, ETA %s", rateStr, remaining.Truncate(time.Second))
+	})
+}
+
+// UnboundedTrackerOption configures an UnboundedTracker.
+type UnboundedTrackerOption func(*unboundedTrackerConfig)
+
+type unboundedTrackerConfig struct {
+	maxFileBars int
+}
+
+// WithUnboundedMaxFileBars caps the number of simultaneously visible
+// per-file sub-bars, collapsing the rest into a "+N more" summary.
+// Defaults to 8.
+func WithUnboundedMaxFileBars(n int) UnboundedTrackerOption {
+	return func(c *unboundedTrackerConfig) {
+		c.maxFileBars = n
 	}
 }
 
 // UnboundedTracker shows the progress of an operation without a predefined size.
 // Falls back to DefaultTracker if not in a terminal.
-func UnboundedTracker(ctx context.Context) ProgressTrackerWithStatus {
+func UnboundedTracker(ctx context.Context, opts ...UnboundedTrackerOption) ProgressTrackerWithStatus {
 	if !terminal.IsTerminal(int(os.Stdout.Fd())) {
 		return DefaultTracker()
 	}
 
+	cfg := &unboundedTrackerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	p := &ProgressUpdate{}
 	progress := mpb.NewWithContext(ctx, mpb.WithWidth(0))
 	fileBar := progress.AddBar(0, mpb.PrependDecorators(
@@ -119,11 +252,13 @@ func UnboundedTracker(ctx context.Context) ProgressTrackerWithStatus {
 		decor.OnComplete(decor.Spinner(nil, decor.WCSyncSpace), "✔")))
 
 	return &unboundedTracker{
+		ctx:      ctx,
 		start:    time.Now(),
 		p:        p,
 		progress: progress,
 		fileBar:  fileBar,
 		byteBar:  byteBar,
+		files:    newFileBars(progress, cfg.maxFileBars),
 	}
 }
 
@@ -168,10 +303,40 @@ func (t *nopTracker) Close() error {
 	return nil
 }
 
+// sink receives the events a progressTracker produces, so the same
+// update/complete bookkeeping can be routed to stdout, a structured logger,
+// or any other destination.
+type sink interface {
+	update(p *ProgressUpdate)
+	complete(p *ProgressUpdate, elapsed time.Duration)
+}
+
+// stdoutSink is the sink DefaultTracker uses: one line per Update and a
+// summary line on Close, printed to stdout.
+type stdoutSink struct{}
+
+func (stdoutSink) update(p *ProgressUpdate) {
+	fmt.Printf(
+		"Complete: %8d files, %-10s In Progress: %8d files, %-10s\n",
+		p.FilesWritten,
+		formatBytes(p.BytesWritten),
+		p.FilesPending,
+		formatBytes(p.BytesPending),
+	)
+}
+
+func (stdoutSink) complete(p *ProgressUpdate, elapsed time.Duration) {
+	printCompletionMessage(p, elapsed)
+}
+
 type progressTracker struct {
-	lock  sync.Mutex
-	p     ProgressUpdate
-	start time.Time
+	lock        sync.Mutex
+	ctx         context.Context
+	p           ProgressUpdate
+	start       time.Time
+	sink        sink
+	minInterval time.Duration
+	lastEmit    time.Time
 }
 
 func (t *progressTracker) Update(u *ProgressUpdate) {
@@ -180,13 +345,13 @@ func (t *progressTracker) Update(u *ProgressUpdate) {
 
 	t.p.update(u)
 
-	fmt.Printf(
-		"Complete: %8d files, %-10s In Progress: %8d files, %-10s\n",
-		t.p.FilesWritten,
-		formatBytes(t.p.BytesWritten),
-		t.p.FilesPending,
-		formatBytes(t.p.BytesPending),
-	)
+	now := time.Now()
+	if !t.lastEmit.IsZero() && now.Sub(t.lastEmit) < t.minInterval {
+		return
+	}
+	t.lastEmit = now
+
+	t.sink.update(&t.p)
 }
 
 func (t *progressTracker) Status() *ProgressUpdate {
@@ -197,16 +362,20 @@ func (t *progressTracker) Status() *ProgressUpdate {
 }
 
 func (t *progressTracker) Close() error {
-	printCompletionMessage(&t.p, time.Since(t.start))
+	t.sink.complete(&t.p, time.Since(t.start))
 	return nil
 }
 
 type boundedTracker struct {
-	lock             sync.Mutex
-	start            time.Time
-	p                *ProgressUpdate
-	progress         *mpb.Progress
-	fileBar, byteBar *mpb.Bar
+	lock                   sync.Mutex
+	ctx                    context.Context
+	start                  time.Time
+	p                      *ProgressUpdate
+	progress               *mpb.Progress
+	fileBar, byteBar       *mpb.Bar
+	fileRate, byteRate     RateEstimator
+	totalFiles, totalBytes int64
+	files                  *fileBars
 }
 
 func (t *boundedTracker) Update(u *ProgressUpdate) {
@@ -215,6 +384,10 @@ func (t *boundedTracker) Update(u *ProgressUpdate) {
 
 	t.p.update(u)
 
+	now := time.Now()
+	t.fileRate.Sample(u.FilesWritten, now)
+	t.byteRate.Sample(u.BytesWritten, now)
+
 	t.fileBar.SetCurrent(t.p.FilesWritten)
 	t.byteBar.SetCurrent(t.p.BytesWritten)
 }
@@ -226,6 +399,29 @@ func (t *boundedTracker) Status() *ProgressUpdate {
 	return t.p.clone()
 }
 
+// StatusWithRate returns the current status along with the smoothed
+// file and byte rates and their ETAs, so a non-terminal caller or a custom
+// sink can report ETA without re-implementing the smoothing math.
+func (t *boundedTracker) StatusWithRate() *RateStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	status := &RateStatus{
+		ProgressUpdate: t.p.clone(),
+		FilesPerSecond: t.fileRate.Rate(),
+		BytesPerSecond: t.byteRate.Rate(),
+	}
+	status.FileETA, status.HasFileETA = remainingETA(t.p.FilesWritten, t.totalFiles, status.FilesPerSecond)
+	status.ByteETA, status.HasByteETA = remainingETA(t.p.BytesWritten, t.totalBytes, status.BytesPerSecond)
+	return status
+}
+
+// BeginFile starts a per-file sub-bar for path, shown nested under the
+// aggregate file/byte bars for the duration of the transfer.
+func (t *boundedTracker) BeginFile(path string, size int64) FileProgress {
+	return t.files.begin(path, size)
+}
+
 func (t *boundedTracker) Close() error {
 	t.fileBar.SetTotal(t.fileBar.Current(), true)
 	t.byteBar.SetTotal(t.byteBar.Current(), true)
@@ -236,10 +432,12 @@ func (t *boundedTracker) Close() error {
 
 type unboundedTracker struct {
 	lock             sync.Mutex
+	ctx              context.Context
 	start            time.Time
 	p                *ProgressUpdate
 	progress         *mpb.Progress
 	fileBar, byteBar *mpb.Bar
+	files            *fileBars
 }
 
 func (t *unboundedTracker) Update(u *ProgressUpdate) {
@@ -272,6 +470,12 @@ func (t *unboundedTracker) Status() *ProgressUpdate {
 	return t.p.clone()
 }
 
+// BeginFile starts a per-file sub-bar for path, shown nested under the
+// aggregate file/byte bars for the duration of the transfer.
+func (t *unboundedTracker) BeginFile(path string, size int64) FileProgress {
+	return t.files.begin(path, size)
+}
+
 type decorator struct {
 	decor.WC
 	f func(s *decor.Statistics) string
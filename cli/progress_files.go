@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/vbauerster/mpb/v4"
+	"github.com/vbauerster/mpb/v4/decor"
+)
+
+// defaultMaxFileBars is the default number of per-file sub-bars shown at
+// once before newly started files collapse into the "+N more" summary.
+const defaultMaxFileBars = 8
+
+// FileProgress tracks the progress of a single in-flight file, shown as its
+// own transient sub-bar under the aggregate file/byte bars.
+type FileProgress interface {
+	// Advance reports that n additional bytes of this file have transferred.
+	Advance(n int64)
+	// Done marks the file as finished, removing its sub-bar. A non-nil err
+	// drops the bar immediately rather than letting it show as complete.
+	Done(err error)
+}
+
+// ProgressTrackerWithFiles is a ProgressTracker that can show per-file
+// sub-bars for concurrent transfers, e.g. BoundedTracker and UnboundedTracker.
+type ProgressTrackerWithFiles interface {
+	ProgressTracker
+	BeginFile(path string, size int64) FileProgress
+}
+
+type nopFileProgress struct{}
+
+func (nopFileProgress) Advance(n int64) {}
+func (nopFileProgress) Done(err error)  {}
+
+func (t *nopTracker) BeginFile(path string, size int64) FileProgress {
+	return nopFileProgress{}
+}
+
+func (t *progressTracker) BeginFile(path string, size int64) FileProgress {
+	return nopFileProgress{}
+}
+
+// fileBars caps the number of simultaneously visible per-file sub-bars on an
+// mpb.Progress, collapsing the rest into a single "+N more" summary bar.
+type fileBars struct {
+	lock     sync.Mutex
+	progress *mpb.Progress
+	max      int
+	active   int
+	overflow int
+	summary  *mpb.Bar
+}
+
+func newFileBars(progress *mpb.Progress, max int) *fileBars {
+	if max <= 0 {
+		max = defaultMaxFileBars
+	}
+	return &fileBars{progress: progress, max: max}
+}
+
+func (f *fileBars) begin(path string, size int64) FileProgress {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.active >= f.max {
+		f.overflow++
+		f.updateSummary()
+		return &overflowFileProgress{bars: f}
+	}
+
+	f.active++
+	name := filepath.Base(path)
+	bar := f.progress.AddBar(size,
+		mpb.BarRemoveOnComplete(),
+		mpb.PrependDecorators(decor.Name(name)),
+		mpb.AppendDecorators(byteRatioDecorator, percentageDecorator))
+	return &barFileProgress{bars: f, bar: bar}
+}
+
+func (f *fileBars) endFile() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.active--
+}
+
+func (f *fileBars) endOverflow() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.overflow--
+	f.updateSummary()
+}
+
+// updateSummary shows or hides the "+N more" bar. Callers must hold f.lock.
+func (f *fileBars) updateSummary() {
+	if f.overflow <= 0 {
+		if f.summary != nil {
+			f.summary.SetTotal(f.summary.Current(), true)
+			f.summary = nil
+		}
+		return
+	}
+
+	if f.summary == nil {
+		f.summary = f.progress.AddBar(0, mpb.BarRemoveOnComplete(),
+			mpb.PrependDecorators(newDecorator(func(s *decor.Statistics) string {
+				return fmt.Sprintf("+%d more", f.overflow)
+			})))
+		return
+	}
+	f.summary.SetTotal(0, false)
+}
+
+type barFileProgress struct {
+	bars *fileBars
+	bar  *mpb.Bar
+}
+
+func (p *barFileProgress) Advance(n int64) {
+	p.bar.IncrBy(int(n))
+}
+
+func (p *barFileProgress) Done(err error) {
+	if err != nil {
+		p.bar.Abort(true)
+	} else {
+		p.bar.SetTotal(p.bar.Current(), true)
+	}
+	p.bars.endFile()
+}
+
+type overflowFileProgress struct {
+	bars *fileBars
+}
+
+func (p *overflowFileProgress) Advance(n int64) {}
+
+func (p *overflowFileProgress) Done(err error) {
+	p.bars.endOverflow()
+}
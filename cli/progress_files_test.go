@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/vbauerster/mpb/v4"
+)
+
+func TestFileBarsOverflow(t *testing.T) {
+	progress := mpb.NewWithContext(context.Background(), mpb.WithWidth(50))
+	bars := newFileBars(progress, 2)
+
+	a := bars.begin("a.txt", 100)
+	b := bars.begin("b.txt", 100)
+	c := bars.begin("c.txt", 100) // exceeds max: should overflow.
+
+	if _, ok := c.(*overflowFileProgress); !ok {
+		t.Fatalf("begin() past max = %T, want *overflowFileProgress", c)
+	}
+	if bars.overflow != 1 {
+		t.Errorf("overflow = %d, want 1", bars.overflow)
+	}
+	if bars.summary == nil {
+		t.Error("expected a summary bar once overflow > 0")
+	}
+
+	c.Done(nil)
+	if bars.overflow != 0 {
+		t.Errorf("overflow after Done = %d, want 0", bars.overflow)
+	}
+	if bars.summary != nil {
+		t.Error("expected the summary bar to clear once overflow returns to 0")
+	}
+
+	a.Done(nil)
+	b.Done(nil)
+	if bars.active != 0 {
+		t.Errorf("active after all Done = %d, want 0", bars.active)
+	}
+}
+
+func TestFileBarsConcurrent(t *testing.T) {
+	progress := mpb.NewWithContext(context.Background(), mpb.WithWidth(50))
+	const max = 4
+	bars := newFileBars(progress, max)
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fp := bars.begin("file", 100)
+			fp.Advance(10)
+			fp.Done(nil)
+		}(i)
+	}
+	wg.Wait()
+
+	bars.lock.Lock()
+	defer bars.lock.Unlock()
+	if bars.active != 0 {
+		t.Errorf("active after all goroutines finished = %d, want 0", bars.active)
+	}
+	if bars.overflow != 0 {
+		t.Errorf("overflow after all goroutines finished = %d, want 0", bars.overflow)
+	}
+}
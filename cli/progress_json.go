@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// JSONTrackerOption configures a JSONTracker.
+type JSONTrackerOption func(*jsonTracker)
+
+// WithMinUpdateInterval coalesces frequent Update calls into at most one
+// JSON event per interval. Without this, JSONTracker writes an event per
+// Update call, which can flood the output under high concurrency.
+func WithMinUpdateInterval(d time.Duration) JSONTrackerOption {
+	return func(t *jsonTracker) {
+		t.minInterval = d
+	}
+}
+
+// WithTotals enables percent-complete and ETA reporting by giving the
+// tracker the expected totals for a bounded operation. Omit this option
+// for unbounded operations; percent and ETA are then left out of events.
+func WithTotals(totalFiles, totalBytes int64) JSONTrackerOption {
+	return func(t *jsonTracker) {
+		t.totalFiles = totalFiles
+		t.totalBytes = totalBytes
+	}
+}
+
+// jsonEvent is a single newline-delimited JSON progress event.
+type jsonEvent struct {
+	Type                string         `json:"type"`
+	Time                time.Time      `json:"time"`
+	ElapsedSeconds      float64        `json:"elapsedSeconds"`
+	Update              ProgressUpdate `json:"update"`
+	BytesPerSecond      float64        `json:"bytesPerSecond"`
+	PercentComplete     *float64       `json:"percentComplete,omitempty"`
+	ETASeconds          *float64       `json:"etaSeconds,omitempty"`
+	FilesPerSecond      float64        `json:"filesPerSecond,omitempty"`
+	FilePercentComplete *float64       `json:"filePercentComplete,omitempty"`
+	FileETASeconds      *float64       `json:"fileEtaSeconds,omitempty"`
+	Error               string         `json:"error,omitempty"`
+}
+
+// JSONTracker writes newline-delimited JSON progress events to w instead of
+// the human-readable output DefaultTracker and BoundedTracker print. It's
+// meant for callers wrapping the tool (CI, orchestrators) that need to
+// parse progress reliably.
+func JSONTracker(w io.Writer, opts ...JSONTrackerOption) ProgressTrackerWithStatus {
+	t := &jsonTracker{
+		ctx:      context.Background(),
+		enc:      json.NewEncoder(w),
+		start:    time.Now(),
+		fileRate: NewEWMARate(defaultEWMAWindow),
+		byteRate: NewEWMARate(defaultEWMAWindow),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+type jsonTracker struct {
+	lock sync.Mutex
+	ctx  context.Context
+	enc  *json.Encoder
+	p    ProgressUpdate
+	start,
+	lastEmit time.Time
+	minInterval            time.Duration
+	totalFiles, totalBytes int64
+	fileRate, byteRate     RateEstimator
+}
+
+func (t *jsonTracker) Update(u *ProgressUpdate) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.p.update(u)
+
+	now := time.Now()
+	t.fileRate.Sample(u.FilesWritten, now)
+	t.byteRate.Sample(u.BytesWritten, now)
+
+	if !t.lastEmit.IsZero() && now.Sub(t.lastEmit) < t.minInterval {
+		return
+	}
+	t.lastEmit = now
+
+	t.emit("status", now, nil)
+}
+
+func (t *jsonTracker) Status() *ProgressUpdate {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.p.clone()
+}
+
+func (t *jsonTracker) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.emit("summary", time.Now(), nil)
+}
+
+// ReportError emits an "error" event carrying the current progress snapshot
+// alongside err, for callers that want to surface a failure without waiting
+// for Close.
+func (t *jsonTracker) ReportError(err error) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.emit("error", time.Now(), err)
+}
+
+// BeginFile implements ProgressTrackerWithFiles. Per-file sub-bars don't
+// translate to a JSON feed, so this is a no-op, like progressTracker's.
+func (t *jsonTracker) BeginFile(path string, size int64) FileProgress {
+	return nopFileProgress{}
+}
+
+// emit writes a single JSON event for the tracker's current state. Callers
+// must hold t.lock.
+func (t *jsonTracker) emit(typ string, now time.Time, err error) error {
+	elapsed := now.Sub(t.start)
+
+	event := jsonEvent{
+		Type:           typ,
+		Time:           now,
+		ElapsedSeconds: elapsed.Seconds(),
+		Update:         *t.p.clone(),
+		BytesPerSecond: t.byteRate.Rate(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if t.totalBytes > 0 {
+		if percent := percentComplete(t.p.BytesWritten, t.totalBytes); percent != nil {
+			event.PercentComplete = percent
+			if remaining, ok := remainingETA(t.p.BytesWritten, t.totalBytes, event.BytesPerSecond); ok {
+				seconds := remaining.Seconds()
+				event.ETASeconds = &seconds
+			}
+		}
+	}
+	if t.totalFiles > 0 {
+		event.FilesPerSecond = t.fileRate.Rate()
+		if percent := percentComplete(t.p.FilesWritten, t.totalFiles); percent != nil {
+			event.FilePercentComplete = percent
+			if remaining, ok := remainingETA(t.p.FilesWritten, t.totalFiles, event.FilesPerSecond); ok {
+				seconds := remaining.Seconds()
+				event.FileETASeconds = &seconds
+			}
+		}
+	}
+
+	return t.enc.Encode(event)
+}
+
+func percentComplete(current, total int64) *float64 {
+	if total <= 0 {
+		return nil
+	}
+	percent := 100 * float64(current) / float64(total)
+	return &percent
+}
+
+// TrackerOutput controls which tracker SelectTracker constructs. JSON forces
+// JSONTracker; AutoJSON falls back to JSONTracker when stdout isn't a
+// terminal, so scripts and CI invocations piping our output get a parseable
+// feed without needing to pass the flag explicitly.
+type TrackerOutput struct {
+	// JSON corresponds to a command's --json flag.
+	JSON bool
+	// AutoJSON corresponds to a command's flag requesting JSON output
+	// whenever stdout isn't a TTY (e.g. --json=auto).
+	AutoJSON bool
+}
+
+// SelectTracker picks the tracker a command should use for an operation of
+// the given size, given how its output flags were set. It's the single
+// place command code should call to implement "use JSONTracker when --json
+// is passed, or when stdout is not a terminal and a flag requests it";
+// everything else falls back to BoundedTracker's own terminal detection.
+func SelectTracker(ctx context.Context, w io.Writer, totalFiles, totalBytes int64, out TrackerOutput) ProgressTrackerWithStatus {
+	if out.JSON || (out.AutoJSON && !terminal.IsTerminal(int(os.Stdout.Fd()))) {
+		return JSONTracker(w, WithTotals(totalFiles, totalBytes))
+	}
+	return BoundedTracker(ctx, totalFiles, totalBytes)
+}
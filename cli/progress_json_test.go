@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPercentComplete(t *testing.T) {
+	cases := []struct {
+		name           string
+		current, total int64
+		want           *float64
+	}{
+		{"zero total", 10, 0, nil},
+		{"negative total", 10, -1, nil},
+		{"halfway", 50, 100, float64Ptr(50)},
+		{"complete", 100, 100, float64Ptr(100)},
+		{"over total", 150, 100, float64Ptr(150)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := percentComplete(c.current, c.total)
+			if (got == nil) != (c.want == nil) {
+				t.Fatalf("percentComplete() = %v, want %v", got, c.want)
+			}
+			if got != nil && *got != *c.want {
+				t.Errorf("percentComplete() = %v, want %v", *got, *c.want)
+			}
+		})
+	}
+}
+
+func TestJSONTrackerFirstUpdateNotThrottled(t *testing.T) {
+	var buf bytes.Buffer
+	tr := JSONTracker(&buf, WithMinUpdateInterval(time.Hour))
+
+	tr.Update(&ProgressUpdate{BytesWritten: 10})
+	if buf.Len() == 0 {
+		t.Fatal("expected the first Update to emit an event even under a long min interval")
+	}
+
+	buf.Reset()
+	tr.Update(&ProgressUpdate{BytesWritten: 10})
+	if buf.Len() != 0 {
+		t.Fatalf("expected a second Update within the interval to be throttled, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("expected Close to emit a summary event regardless of throttling")
+	}
+}
+
+func float64Ptr(f float64) *float64 { return &f }
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields are structured key-value pairs attached to a single log line.
+type Fields map[string]interface{}
+
+// Logger is the minimal structured-logging interface LoggerTracker needs.
+// It's satisfied by a thin adapter over logrus, zap, or slog, so fileheap
+// can be embedded in a service that already uses one of those without
+// pulling progress output through fmt.Printf.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	WithFields(fields Fields) Logger
+}
+
+// LoggerTrackerOption configures a LoggerTracker.
+type LoggerTrackerOption func(*progressTracker)
+
+// WithLoggerMinInterval throttles a logger-backed tracker to at most one
+// event per interval, since debug-level per-chunk updates would otherwise
+// overwhelm log aggregators.
+func WithLoggerMinInterval(d time.Duration) LoggerTrackerOption {
+	return func(t *progressTracker) {
+		t.minInterval = d
+	}
+}
+
+// LoggerTracker routes progress events to logger instead of stdout, for
+// embedding fileheap in a larger service.
+func LoggerTracker(logger Logger, opts ...LoggerTrackerOption) ProgressTrackerWithStatus {
+	t := &progressTracker{
+		ctx:   context.Background(),
+		start: time.Now(),
+		sink:  loggerSink{logger: logger},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// loggerSink adapts sink to a Logger.
+type loggerSink struct {
+	logger Logger
+}
+
+func (s loggerSink) update(p *ProgressUpdate) {
+	s.logger.WithFields(Fields{
+		"filesWritten": p.FilesWritten,
+		"filesPending": p.FilesPending,
+		"bytesWritten": p.BytesWritten,
+		"bytesPending": p.BytesPending,
+	}).Infof("progress: %d files complete, %s written, %d files and %s in progress",
+		p.FilesWritten, formatBytes(p.BytesWritten), p.FilesPending, formatBytes(p.BytesPending))
+}
+
+func (s loggerSink) complete(p *ProgressUpdate, elapsed time.Duration) {
+	s.logger.WithFields(Fields{
+		"filesWritten": p.FilesWritten,
+		"bytesWritten": p.BytesWritten,
+		"elapsed":      elapsed.String(),
+	}).Infof("completed in %s: %s, %d files/s",
+		elapsed.Truncate(time.Second/10),
+		FormatRate(p.BytesWritten, elapsed),
+		int(math.Round(float64(p.FilesWritten)/elapsed.Seconds())))
+}
+
+// NewLogrusSink adapts a *logrus.Logger to Logger.
+func NewLogrusSink(l *logrus.Logger) Logger {
+	return logrusLogger{l}
+}
+
+type logrusLogger struct {
+	logrus.FieldLogger
+}
+
+func (l logrusLogger) WithFields(fields Fields) Logger {
+	return logrusLogger{l.FieldLogger.WithFields(logrus.Fields(fields))}
+}
+
+// NewSlogSink adapts a *slog.Logger to Logger.
+func NewSlogSink(l *slog.Logger) Logger {
+	return slogLogger{logger: l}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+	attrs  []any
+}
+
+func (l slogLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, args...), l.attrs...)
+}
+
+func (l slogLogger) Infof(format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...), l.attrs...)
+}
+
+func (l slogLogger) Warnf(format string, args ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, args...), l.attrs...)
+}
+
+func (l slogLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...), l.attrs...)
+}
+
+func (l slogLogger) WithFields(fields Fields) Logger {
+	attrs := make([]any, 0, len(l.attrs)+len(fields)*2)
+	attrs = append(attrs, l.attrs...)
+	for k, v := range fields {
+		attrs = append(attrs, k, v)
+	}
+	return slogLogger{logger: l.logger, attrs: attrs}
+}
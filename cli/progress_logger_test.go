@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLogger counts Infof calls instead of formatting/printing anything, so
+// tests can assert on throttling behavior without parsing log output.
+type fakeLogger struct {
+	mu    sync.Mutex
+	infof int
+}
+
+func (l *fakeLogger) Debugf(format string, args ...interface{}) {}
+func (l *fakeLogger) Infof(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.infof++
+}
+func (l *fakeLogger) Warnf(format string, args ...interface{})  {}
+func (l *fakeLogger) Errorf(format string, args ...interface{}) {}
+func (l *fakeLogger) WithFields(fields Fields) Logger           { return l }
+
+func (l *fakeLogger) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.infof
+}
+
+func TestLoggerTrackerThrottlesLikeProgressTracker(t *testing.T) {
+	logger := &fakeLogger{}
+	tr := LoggerTracker(logger, WithLoggerMinInterval(time.Hour))
+
+	tr.Update(&ProgressUpdate{BytesWritten: 10})
+	if got := logger.count(); got != 1 {
+		t.Fatalf("Infof calls after first Update = %d, want 1", got)
+	}
+
+	tr.Update(&ProgressUpdate{BytesWritten: 10})
+	if got := logger.count(); got != 1 {
+		t.Fatalf("Infof calls after throttled Update = %d, want 1", got)
+	}
+
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	if got := logger.count(); got != 2 {
+		t.Fatalf("Infof calls after Close = %d, want 2 (Close always emits)", got)
+	}
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+)
+
+// ProgressTrackerWithIO tracks the status of an operation and can wrap
+// readers and writers to report byte-level progress automatically, instead
+// of requiring callers to call Update with byte deltas by hand. size is the
+// number of bytes the caller already declared pending for r/w (e.g. via
+// Update(&ProgressUpdate{BytesPending: size})); Close uses it to zero out
+// the full remainder if the transfer is aborted partway through, rather than
+// only the bytes that actually made it through. Pass 0 if the size isn't
+// known up front.
+type ProgressTrackerWithIO interface {
+	ProgressTracker
+	ProxyReader(r io.Reader, size int64) io.ReadCloser
+	ProxyWriter(w io.Writer, size int64) io.WriteCloser
+}
+
+func (t *nopTracker) ProxyReader(r io.Reader, size int64) io.ReadCloser {
+	return toReadCloser(r)
+}
+
+func (t *nopTracker) ProxyWriter(w io.Writer, size int64) io.WriteCloser {
+	return toWriteCloser(w)
+}
+
+func (t *progressTracker) ProxyReader(r io.Reader, size int64) io.ReadCloser {
+	return newProxyReader(t.ctx, t, r, size)
+}
+
+func (t *progressTracker) ProxyWriter(w io.Writer, size int64) io.WriteCloser {
+	return newProxyWriter(t.ctx, t, w, size)
+}
+
+func (t *boundedTracker) ProxyReader(r io.Reader, size int64) io.ReadCloser {
+	return newProxyReader(t.ctx, t, r, size)
+}
+
+func (t *boundedTracker) ProxyWriter(w io.Writer, size int64) io.WriteCloser {
+	return newProxyWriter(t.ctx, t, w, size)
+}
+
+func (t *unboundedTracker) ProxyReader(r io.Reader, size int64) io.ReadCloser {
+	return newProxyReader(t.ctx, t, r, size)
+}
+
+func (t *unboundedTracker) ProxyWriter(w io.Writer, size int64) io.WriteCloser {
+	return newProxyWriter(t.ctx, t, w, size)
+}
+
+func (t *jsonTracker) ProxyReader(r io.Reader, size int64) io.ReadCloser {
+	return newProxyReader(t.ctx, t, r, size)
+}
+
+func (t *jsonTracker) ProxyWriter(w io.Writer, size int64) io.WriteCloser {
+	return newProxyWriter(t.ctx, t, w, size)
+}
+
+// proxy emits ProgressUpdate{BytesWritten: n} deltas as bytes flow through
+// it, and on Close settles the BytesPending the caller declared up front in
+// one final delta — the full declared size, not just what was actually
+// copied, so an aborted transfer doesn't leave BytesPending stuck above zero.
+type proxy struct {
+	ctx     context.Context
+	tracker ProgressTracker
+	size    int64
+	total   int64
+}
+
+func (p *proxy) record(n int) {
+	if n <= 0 {
+		return
+	}
+	atomic.AddInt64(&p.total, int64(n))
+	p.tracker.Update(&ProgressUpdate{BytesWritten: int64(n)})
+}
+
+func (p *proxy) close() {
+	pending := p.size
+	if pending <= 0 {
+		pending = atomic.LoadInt64(&p.total)
+	}
+	p.tracker.Update(&ProgressUpdate{BytesPending: -pending})
+}
+
+type proxyReader struct {
+	*proxy
+	r io.Reader
+}
+
+func newProxyReader(ctx context.Context, tracker ProgressTracker, r io.Reader, size int64) io.ReadCloser {
+	return &proxyReader{proxy: &proxy{ctx: ctx, tracker: tracker, size: size}, r: r}
+}
+
+func (pr *proxyReader) Read(b []byte) (int, error) {
+	if err := pr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := pr.r.Read(b)
+	pr.record(n)
+	return n, err
+}
+
+func (pr *proxyReader) Close() error {
+	pr.proxy.close()
+	if c, ok := pr.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+type proxyWriter struct {
+	*proxy
+	w io.Writer
+}
+
+func newProxyWriter(ctx context.Context, tracker ProgressTracker, w io.Writer, size int64) io.WriteCloser {
+	return &proxyWriter{proxy: &proxy{ctx: ctx, tracker: tracker, size: size}, w: w}
+}
+
+func (pw *proxyWriter) Write(b []byte) (int, error) {
+	if err := pw.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := pw.w.Write(b)
+	pw.record(n)
+	return n, err
+}
+
+func (pw *proxyWriter) Close() error {
+	pw.proxy.close()
+	if c, ok := pw.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+type nopReadCloser struct {
+	io.Reader
+}
+
+func (nopReadCloser) Close() error { return nil }
+
+func toReadCloser(r io.Reader) io.ReadCloser {
+	if rc, ok := r.(io.ReadCloser); ok {
+		return rc
+	}
+	return nopReadCloser{r}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func toWriteCloser(w io.Writer) io.WriteCloser {
+	if wc, ok := w.(io.WriteCloser); ok {
+		return wc
+	}
+	return nopWriteCloser{w}
+}
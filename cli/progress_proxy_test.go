@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeProgressTracker records the ProgressUpdate deltas it receives so tests
+// can assert on the net effect without a real tracker implementation.
+type fakeProgressTracker struct {
+	mu           sync.Mutex
+	bytesWritten int64
+	bytesPending int64
+}
+
+func (f *fakeProgressTracker) Update(u *ProgressUpdate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bytesWritten += u.BytesWritten
+	f.bytesPending += u.BytesPending
+}
+
+func (f *fakeProgressTracker) Close() error { return nil }
+
+func TestProxyReaderAbortZeroesDeclaredSize(t *testing.T) {
+	const size = 10 << 20 // 10MB declared up front, as a caller would via Update.
+	ft := &fakeProgressTracker{bytesPending: size}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	src := bytes.NewReader(make([]byte, size))
+	pr := newProxyReader(ctx, ft, src, size)
+
+	buf := make([]byte, 1024)
+	n, err := pr.Read(buf)
+	if err != nil || n != len(buf) {
+		t.Fatalf("Read() = (%d, %v), want (%d, nil)", n, err, len(buf))
+	}
+
+	cancel()
+	if _, err := pr.Read(buf); err == nil {
+		t.Fatal("expected Read after cancellation to return an error")
+	}
+
+	if err := pr.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.bytesPending != 0 {
+		t.Errorf("bytesPending = %d, want 0 (aborted transfer left bytes stuck pending)", ft.bytesPending)
+	}
+	if ft.bytesWritten != int64(len(buf)) {
+		t.Errorf("bytesWritten = %d, want %d", ft.bytesWritten, len(buf))
+	}
+}
+
+func TestProxyReaderUnknownSizeClosesActualBytes(t *testing.T) {
+	ft := &fakeProgressTracker{bytesPending: 5}
+	src := bytes.NewReader(make([]byte, 3))
+	pr := newProxyReader(context.Background(), ft, src, 0)
+
+	buf := make([]byte, 3)
+	if _, err := pr.Read(buf); err != nil {
+		t.Fatalf("Read() = %v", err)
+	}
+	if err := pr.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	// With no declared size, close can only settle what was actually seen.
+	if ft.bytesPending != 2 {
+		t.Errorf("bytesPending = %d, want 2", ft.bytesPending)
+	}
+}
+
+func TestProxyWriterConcurrentWrites(t *testing.T) {
+	ft := &fakeProgressTracker{}
+	pw := newProxyWriter(context.Background(), ft, &discardWriter{}, 0)
+
+	const goroutines, writesEach, chunkSize = 20, 50, 7
+	var wg sync.WaitGroup
+	var wantTotal int64
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			chunk := make([]byte, chunkSize)
+			for j := 0; j < writesEach; j++ {
+				if _, err := pw.Write(chunk); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	wantTotal = int64(goroutines * writesEach * chunkSize)
+
+	ft.mu.Lock()
+	got := ft.bytesWritten
+	ft.mu.Unlock()
+	if got != wantTotal {
+		t.Errorf("bytesWritten = %d, want %d", got, wantTotal)
+	}
+
+	if err := pw.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.bytesPending != -wantTotal {
+		t.Errorf("bytesPending after close = %d, want %d", ft.bytesPending, -wantTotal)
+	}
+}
+
+type discardWriter struct{ n int64 }
+
+func (d *discardWriter) Write(b []byte) (int, error) {
+	atomic.AddInt64(&d.n, int64(len(b)))
+	return len(b), nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"math"
+	"time"
+)
+
+const defaultEWMAWindow = 30 * time.Second
+
+// RateEstimator smooths a stream of byte or file count deltas into a
+// bytes-per-second (or files-per-second) estimate.
+type RateEstimator interface {
+	// Sample records a delta of n observed at time now.
+	Sample(n int64, now time.Time)
+	// Rate returns the current smoothed rate.
+	Rate() float64
+}
+
+// NewEWMARate returns a RateEstimator that smooths samples with an
+// exponentially-weighted moving average over the given window. The weight
+// of past samples decays by a factor of e for each window of elapsed time.
+func NewEWMARate(window time.Duration) RateEstimator {
+	return &ewmaRate{window: window}
+}
+
+type ewmaRate struct {
+	window time.Duration
+	rate   float64
+	last   time.Time
+}
+
+func (e *ewmaRate) Sample(n int64, now time.Time) {
+	if e.last.IsZero() {
+		e.last = now
+		return
+	}
+
+	dt := now.Sub(e.last).Seconds()
+	if dt <= 0 {
+		return
+	}
+	e.last = now
+
+	instant := float64(n) / dt
+	alpha := 1 - math.Exp(-dt/e.window.Seconds())
+	e.rate = alpha*instant + (1-alpha)*e.rate
+}
+
+func (e *ewmaRate) Rate() float64 {
+	return e.rate
+}
+
+// NewSimpleAverageRate returns a RateEstimator that reports the plain
+// average rate over the lifetime of the transfer, with no smoothing window.
+func NewSimpleAverageRate() RateEstimator {
+	return &simpleAverageRate{}
+}
+
+type simpleAverageRate struct {
+	start time.Time
+	last  time.Time
+	total int64
+}
+
+func (s *simpleAverageRate) Sample(n int64, now time.Time) {
+	if s.start.IsZero() {
+		s.start = now
+	}
+	s.last = now
+	s.total += n
+}
+
+func (s *simpleAverageRate) Rate() float64 {
+	elapsed := s.last.Sub(s.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.total) / elapsed
+}
+
+// remainingETA returns the estimated time remaining to go from current to
+// total at rate per second, or false if rate isn't usable yet or current has
+// already reached total.
+func remainingETA(current, total int64, rate float64) (time.Duration, bool) {
+	if rate <= 0 || current >= total {
+		return 0, false
+	}
+	seconds := float64(total-current) / rate
+	return time.Duration(seconds * float64(time.Second)), true
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEWMARate(t *testing.T) {
+	base := time.Unix(0, 0)
+
+	cases := []struct {
+		name    string
+		samples []struct {
+			n  int64
+			at time.Duration
+		}
+		want float64
+	}{
+		{
+			name: "first sample only establishes a baseline",
+			samples: []struct {
+				n  int64
+				at time.Duration
+			}{
+				{1000, 0},
+			},
+			want: 0,
+		},
+		{
+			name: "single interval smooths toward the instant rate",
+			samples: []struct {
+				n  int64
+				at time.Duration
+			}{
+				{0, 0},
+				{1000, time.Second},
+			},
+			// alpha = 1 - exp(-1/10) for a 10s window, instant rate 1000 B/s.
+			want: (1 - math.Exp(-1.0/10)) * 1000,
+		},
+		{
+			name: "non-positive elapsed is ignored",
+			samples: []struct {
+				n  int64
+				at time.Duration
+			}{
+				{0, 0},
+				{1000, time.Second},
+				{1000, time.Second}, // same timestamp again: no-op
+			},
+			want: (1 - math.Exp(-1.0/10)) * 1000,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := NewEWMARate(10 * time.Second)
+			for _, s := range c.samples {
+				e.Sample(s.n, base.Add(s.at))
+			}
+			if got := e.Rate(); math.Abs(got-c.want) > 1e-6 {
+				t.Errorf("Rate() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSimpleAverageRate(t *testing.T) {
+	base := time.Unix(0, 0)
+
+	r := NewSimpleAverageRate()
+	if got := r.Rate(); got != 0 {
+		t.Fatalf("Rate() before any samples = %v, want 0", got)
+	}
+
+	r.Sample(1000, base)
+	r.Sample(1000, base.Add(2*time.Second))
+	// 2000 bytes over 2 seconds, regardless of the per-sample split.
+	if got, want := r.Rate(), 1000.0; got != want {
+		t.Errorf("Rate() = %v, want %v", got, want)
+	}
+}
+
+func TestRemainingETA(t *testing.T) {
+	cases := []struct {
+		name           string
+		current, total int64
+		rate           float64
+		wantOK         bool
+		wantSeconds    float64
+	}{
+		{"zero rate", 10, 100, 0, false, 0},
+		{"negative rate", 10, 100, -5, false, 0},
+		{"already complete", 100, 100, 10, false, 0},
+		{"past total", 150, 100, 10, false, 0},
+		{"normal", 50, 100, 10, true, 5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := remainingETA(c.current, c.total, c.rate)
+			if ok != c.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, c.wantOK)
+			}
+			if ok && got != time.Duration(c.wantSeconds*float64(time.Second)) {
+				t.Errorf("remainingETA = %v, want %v", got, time.Duration(c.wantSeconds*float64(time.Second)))
+			}
+		})
+	}
+}